@@ -0,0 +1,25 @@
+// Author: recallsong
+// Email: songruiguo@qq.com
+
+package etcd
+
+import "time"
+
+const (
+	minRetryBackoff = 100 * time.Millisecond
+	maxRetryBackoff = 10 * time.Second
+)
+
+// nextBackoff doubles prev (starting at minRetryBackoff) up to
+// maxRetryBackoff. It backs off the Watcher/Mirror reconnect loops so a
+// persistently failing etcd doesn't get hammered by a tight retry loop.
+func nextBackoff(prev time.Duration) time.Duration {
+	if prev <= 0 {
+		return minRetryBackoff
+	}
+	next := prev * 2
+	if next > maxRetryBackoff {
+		return maxRetryBackoff
+	}
+	return next
+}
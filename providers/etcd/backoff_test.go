@@ -0,0 +1,27 @@
+// Author: recallsong
+// Email: songruiguo@qq.com
+
+package etcd
+
+import "testing"
+
+func TestNextBackoff(t *testing.T) {
+	got := nextBackoff(0)
+	if got != minRetryBackoff {
+		t.Fatalf("expected first backoff to be %s, got %s", minRetryBackoff, got)
+	}
+	prev := got
+	for i := 0; i < 10; i++ {
+		next := nextBackoff(prev)
+		if next < prev {
+			t.Fatalf("backoff must not shrink: prev=%s next=%s", prev, next)
+		}
+		if next > maxRetryBackoff {
+			t.Fatalf("backoff must not exceed cap: next=%s cap=%s", next, maxRetryBackoff)
+		}
+		prev = next
+	}
+	if prev != maxRetryBackoff {
+		t.Fatalf("expected backoff to converge to the cap %s, got %s", maxRetryBackoff, prev)
+	}
+}
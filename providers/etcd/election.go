@@ -0,0 +1,114 @@
+// Author: recallsong
+// Email: songruiguo@qq.com
+
+package etcd
+
+import (
+	"context"
+	"sync"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/clientv3/concurrency"
+)
+
+// Election is a leader-election service built on concurrency.Election.
+type Election interface {
+	// Campaign blocks until this process is elected leader under prefix.
+	Campaign(ctx context.Context, prefix, value string) error
+	// Resign gives up leadership previously won by Campaign.
+	Resign(ctx context.Context) error
+	// Leader returns the current leader's value under prefix.
+	Leader(ctx context.Context, prefix string) (string, error)
+	// Observe streams leader values under prefix as they change.
+	Observe(ctx context.Context, prefix string) (<-chan string, error)
+}
+
+type election struct {
+	client *clientv3.Client
+
+	mu   sync.Mutex
+	sess *concurrency.Session
+	elec *concurrency.Election
+}
+
+func newElection(client *clientv3.Client) *election {
+	return &election{client: client}
+}
+
+// Campaign implements the Election interface.
+func (e *election) Campaign(ctx context.Context, prefix, value string) error {
+	// Close any session already held under a previous Campaign first,
+	// otherwise its lease keeps renewing and its keepalive goroutine is
+	// orphaned with no reference left to close it.
+	e.mu.Lock()
+	prevSess := e.sess
+	e.sess, e.elec = nil, nil
+	e.mu.Unlock()
+	if prevSess != nil {
+		prevSess.Close()
+	}
+
+	sess, err := concurrency.NewSession(e.client)
+	if err != nil {
+		return err
+	}
+	elec := concurrency.NewElection(sess, prefix)
+	if err := elec.Campaign(ctx, value); err != nil {
+		sess.Close()
+		return err
+	}
+	e.mu.Lock()
+	e.sess, e.elec = sess, elec
+	e.mu.Unlock()
+	return nil
+}
+
+// Resign implements the Election interface.
+func (e *election) Resign(ctx context.Context) error {
+	e.mu.Lock()
+	sess, elec := e.sess, e.elec
+	e.sess, e.elec = nil, nil
+	e.mu.Unlock()
+	if elec == nil {
+		return nil
+	}
+	err := elec.Resign(ctx)
+	sess.Close()
+	return err
+}
+
+// Leader implements the Election interface.
+func (e *election) Leader(ctx context.Context, prefix string) (string, error) {
+	sess, err := concurrency.NewSession(e.client)
+	if err != nil {
+		return "", err
+	}
+	defer sess.Close()
+	resp, err := concurrency.NewElection(sess, prefix).Leader(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(resp.Kvs[0].Value), nil
+}
+
+// Observe implements the Election interface.
+func (e *election) Observe(ctx context.Context, prefix string) (<-chan string, error) {
+	sess, err := concurrency.NewSession(e.client)
+	if err != nil {
+		return nil, err
+	}
+	elec := concurrency.NewElection(sess, prefix)
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		defer sess.Close()
+		for resp := range elec.Observe(ctx) {
+			select {
+			case out <- string(resp.Kvs[0].Value):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
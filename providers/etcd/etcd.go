@@ -5,8 +5,6 @@ package etcd
 
 import (
 	"crypto/tls"
-	"crypto/x509"
-	"io/ioutil"
 	"os"
 	"reflect"
 	"strings"
@@ -15,6 +13,7 @@ import (
 	"github.com/coreos/etcd/clientv3"
 	"github.com/erda-project/erda-infra/base/logs"
 	"github.com/erda-project/erda-infra/base/servicehub"
+	"github.com/erda-project/erda-infra/providers/etcd/internal/tlsconf"
 )
 
 // Interface .
@@ -32,17 +31,32 @@ type config struct {
 		CertKeyFile string `file:"cert_key_file"`
 		CaFile      string `file:"ca_file"`
 	} `file:"tls"`
+	Mirror struct {
+		SnapCount int64 `file:"snap_count" default:"1000"`
+	} `file:"mirror"`
 }
 
-var clientType = reflect.TypeOf((*clientv3.Client)(nil))
+var (
+	clientType       = reflect.TypeOf((*clientv3.Client)(nil))
+	watcherType      = reflect.TypeOf((*Watcher)(nil)).Elem()
+	leaseManagerType = reflect.TypeOf((*LeaseManager)(nil)).Elem()
+	electionType     = reflect.TypeOf((*Election)(nil)).Elem()
+	mirrorType       = reflect.TypeOf((*Mirror)(nil)).Elem()
+)
 
 type define struct{}
 
-func (d *define) Services() []string { return []string{"etcd", "etcd-client"} }
+func (d *define) Services() []string {
+	return []string{"etcd", "etcd-client", "etcd-watcher", "etcd-lease", "etcd-election", "etcd-mirror"}
+}
 func (d *define) Types() []reflect.Type {
 	return []reflect.Type{
 		reflect.TypeOf((*Interface)(nil)).Elem(),
 		clientType,
+		watcherType,
+		leaseManagerType,
+		electionType,
+		mirrorType,
 	}
 }
 func (d *define) Description() string { return "etcd" }
@@ -58,6 +72,11 @@ type provider struct {
 	Log       logs.Logger
 	client    *clientv3.Client
 	tlsConfig *tls.Config
+
+	watcher      *watcher
+	leaseManager *leaseManager
+	election     *election
+	mirror       *mirror
 }
 
 func (p *provider) Init(ctx servicehub.Context) error {
@@ -70,6 +89,10 @@ func (p *provider) Init(ctx servicehub.Context) error {
 		return err
 	}
 	p.client = client
+	p.watcher = newWatcher(client, p.Log)
+	p.leaseManager = newLeaseManager(client, p.Log)
+	p.election = newElection(client)
+	p.mirror = newMirror(client, p.Log, p.Cfg.Mirror.SnapCount)
 	return nil
 }
 
@@ -88,7 +111,7 @@ func (p *provider) Timeout() time.Duration { return p.Cfg.Timeout }
 
 func (p *provider) initTLSConfig() error {
 	if len(p.Cfg.TLS.CertFile) > 0 || len(p.Cfg.TLS.CertKeyFile) > 0 {
-		cfg, err := readTLSConfig(p.Cfg.TLS.CertFile, p.Cfg.TLS.CertKeyFile, p.Cfg.TLS.CaFile)
+		cfg, err := tlsconf.Load(p.Cfg.TLS.CertFile, p.Cfg.TLS.CertKeyFile, p.Cfg.TLS.CaFile)
 		if err != nil {
 			if os.IsNotExist(err) {
 				p.Log.Warnf("fail to load tls files: %s", err)
@@ -101,27 +124,19 @@ func (p *provider) initTLSConfig() error {
 	return nil
 }
 
-func readTLSConfig(certFile, certKeyFile, caFile string) (*tls.Config, error) {
-	cert, err := tls.LoadX509KeyPair(certFile, certKeyFile)
-	if err != nil {
-		return nil, err
-	}
-	caData, err := ioutil.ReadFile(caFile)
-	if err != nil {
-		return nil, err
-	}
-	pool := x509.NewCertPool()
-	pool.AppendCertsFromPEM(caData)
-	return &tls.Config{
-		Certificates: []tls.Certificate{cert},
-		RootCAs:      pool,
-	}, nil
-}
-
 // Provide .
 func (p *provider) Provide(ctx servicehub.DependencyContext, args ...interface{}) interface{} {
-	if ctx.Type() == clientType || ctx.Service() == "etcd-client" {
+	switch {
+	case ctx.Type() == clientType || ctx.Service() == "etcd-client":
 		return p.client
+	case ctx.Type() == watcherType || ctx.Service() == "etcd-watcher":
+		return p.watcher
+	case ctx.Type() == leaseManagerType || ctx.Service() == "etcd-lease":
+		return p.leaseManager
+	case ctx.Type() == electionType || ctx.Service() == "etcd-election":
+		return p.election
+	case ctx.Type() == mirrorType || ctx.Service() == "etcd-mirror":
+		return p.mirror
 	}
 	return p
 }
@@ -0,0 +1,30 @@
+// Author: recallsong
+// Email: songruiguo@qq.com
+
+// Package tlsconf loads the client TLS config shared by the etcd v3 and v2
+// providers.
+package tlsconf
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+)
+
+// Load reads a client certificate/key pair and CA bundle into a *tls.Config.
+func Load(certFile, certKeyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, certKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	caData, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(caData)
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+	}, nil
+}
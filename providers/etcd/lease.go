@@ -0,0 +1,121 @@
+// Author: recallsong
+// Email: songruiguo@qq.com
+
+package etcd
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/erda-project/erda-infra/base/logs"
+)
+
+// ExpireFunc is invoked when a named lease's keepalive stops, because the
+// lease expired, was revoked, or the keepalive stream was lost.
+type ExpireFunc func(name string, err error)
+
+// leaseClient is the subset of clientv3.Lease that leaseManager needs,
+// satisfied by *clientv3.Client. Narrowing to this interface lets tests
+// exercise Grant/Revoke bookkeeping with a fake client.
+type leaseClient interface {
+	Grant(ctx context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error)
+	Revoke(ctx context.Context, id clientv3.LeaseID) (*clientv3.LeaseRevokeResponse, error)
+	KeepAlive(ctx context.Context, id clientv3.LeaseID) (<-chan *clientv3.LeaseKeepAliveResponse, error)
+}
+
+// LeaseManager keepalives named leases and notifies callers via ExpireFunc
+// when one of them expires.
+type LeaseManager interface {
+	Grant(ctx context.Context, name string, ttl int64, onExpire ExpireFunc) (clientv3.LeaseID, error)
+	Revoke(name string) error
+	LeaseID(name string) (clientv3.LeaseID, bool)
+}
+
+type managedLease struct {
+	id     clientv3.LeaseID
+	cancel context.CancelFunc
+}
+
+type leaseManager struct {
+	client leaseClient
+	log    logs.Logger
+
+	mu     sync.Mutex
+	leases map[string]*managedLease
+}
+
+func newLeaseManager(client leaseClient, log logs.Logger) *leaseManager {
+	return &leaseManager{client: client, log: log, leases: make(map[string]*managedLease)}
+}
+
+// Grant implements the LeaseManager interface.
+func (m *leaseManager) Grant(ctx context.Context, name string, ttl int64, onExpire ExpireFunc) (clientv3.LeaseID, error) {
+	// Revoke any lease already held under name first, otherwise its keepalive
+	// goroutine is orphaned and keeps renewing on etcd with no way back to it.
+	if err := m.Revoke(name); err != nil {
+		return 0, err
+	}
+	resp, err := m.client.Grant(ctx, ttl)
+	if err != nil {
+		return 0, err
+	}
+	keepAliveCtx, cancel := context.WithCancel(context.Background())
+	ch, err := m.client.KeepAlive(keepAliveCtx, resp.ID)
+	if err != nil {
+		cancel()
+		return 0, err
+	}
+	m.mu.Lock()
+	m.leases[name] = &managedLease{id: resp.ID, cancel: cancel}
+	m.mu.Unlock()
+	go m.keepalive(keepAliveCtx, name, ch, onExpire)
+	return resp.ID, nil
+}
+
+func (m *leaseManager) keepalive(ctx context.Context, name string, ch <-chan *clientv3.LeaseKeepAliveResponse, onExpire ExpireFunc) {
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				m.mu.Lock()
+				delete(m.leases, name)
+				m.mu.Unlock()
+				if onExpire != nil {
+					onExpire(name, errors.New("lease keepalive channel closed"))
+				}
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Revoke implements the LeaseManager interface.
+func (m *leaseManager) Revoke(name string) error {
+	m.mu.Lock()
+	l, ok := m.leases[name]
+	if ok {
+		delete(m.leases, name)
+	}
+	m.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	l.cancel()
+	_, err := m.client.Revoke(context.Background(), l.id)
+	return err
+}
+
+// LeaseID implements the LeaseManager interface.
+func (m *leaseManager) LeaseID(name string) (clientv3.LeaseID, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	l, ok := m.leases[name]
+	if !ok {
+		return 0, false
+	}
+	return l.id, true
+}
@@ -0,0 +1,72 @@
+// Author: recallsong
+// Email: songruiguo@qq.com
+
+package etcd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coreos/etcd/clientv3"
+)
+
+type fakeLeaseClient struct {
+	nextID  clientv3.LeaseID
+	granted []clientv3.LeaseID
+	revoked []clientv3.LeaseID
+}
+
+func (f *fakeLeaseClient) Grant(ctx context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error) {
+	f.nextID++
+	f.granted = append(f.granted, f.nextID)
+	return &clientv3.LeaseGrantResponse{ID: f.nextID}, nil
+}
+
+func (f *fakeLeaseClient) Revoke(ctx context.Context, id clientv3.LeaseID) (*clientv3.LeaseRevokeResponse, error) {
+	f.revoked = append(f.revoked, id)
+	return &clientv3.LeaseRevokeResponse{}, nil
+}
+
+func (f *fakeLeaseClient) KeepAlive(ctx context.Context, id clientv3.LeaseID) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	ch := make(chan *clientv3.LeaseKeepAliveResponse)
+	return ch, nil
+}
+
+func TestLeaseManagerGrantRevokesExisting(t *testing.T) {
+	client := &fakeLeaseClient{}
+	m := newLeaseManager(client, nil)
+
+	firstID, err := m.Grant(context.Background(), "name", 10, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	secondID, err := m.Grant(context.Background(), "name", 10, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if secondID == firstID {
+		t.Fatalf("expected a new lease id, got the same one %d", firstID)
+	}
+
+	if len(client.revoked) != 1 || client.revoked[0] != firstID {
+		t.Fatalf("expected first lease %d to be revoked before re-granting, revoked=%v", firstID, client.revoked)
+	}
+
+	id, ok := m.LeaseID("name")
+	if !ok || id != secondID {
+		t.Fatalf("expected LeaseID to reflect the latest lease %d, got %d (ok=%v)", secondID, id, ok)
+	}
+}
+
+func TestLeaseManagerRevokeUnknownNameIsNoop(t *testing.T) {
+	client := &fakeLeaseClient{}
+	m := newLeaseManager(client, nil)
+
+	if err := m.Revoke("missing"); err != nil {
+		t.Fatalf("expected no error revoking an unknown name, got %s", err)
+	}
+	if len(client.revoked) != 0 {
+		t.Fatalf("expected no revoke call against the client, got %v", client.revoked)
+	}
+}
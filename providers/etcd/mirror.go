@@ -0,0 +1,162 @@
+// Author: recallsong
+// Email: songruiguo@qq.com
+
+package etcd
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/erda-project/erda-infra/base/logs"
+)
+
+// Mirror maintains an in-memory snapshot of all keys under a prefix, kept up
+// to date by watch events. Once the watch has missed SnapCount revisions
+// since the last full sync (the compacted gap each time a watch fails on a
+// compaction, one for any other watch failure), it falls back to a full
+// resync instead of trying to patch the snapshot incrementally.
+type Mirror interface {
+	// Sync starts mirroring prefix and blocks until the initial snapshot is loaded.
+	Sync(ctx context.Context, prefix string) error
+	// Get returns the current value for key, if present in the snapshot.
+	Get(key string) ([]byte, bool)
+	// Snapshot returns a copy of the full in-memory snapshot.
+	Snapshot() map[string][]byte
+}
+
+// mirrorClient is the subset of clientv3.KV and clientv3.Watcher that mirror
+// needs, satisfied by *clientv3.Client. Narrowing to this interface lets
+// tests drive the full-sync/watch loop with a fake client.
+type mirrorClient interface {
+	watchClient
+	Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error)
+}
+
+type mirror struct {
+	client    mirrorClient
+	log       logs.Logger
+	snapCount int64
+
+	mu     sync.RWMutex
+	data   map[string][]byte
+	rev    int64
+	missed int64
+}
+
+func newMirror(client mirrorClient, log logs.Logger, snapCount int64) *mirror {
+	return &mirror{client: client, log: log, snapCount: snapCount, data: make(map[string][]byte)}
+}
+
+// Sync implements the Mirror interface.
+func (m *mirror) Sync(ctx context.Context, prefix string) error {
+	if err := m.fullSync(ctx, prefix); err != nil {
+		return err
+	}
+	go m.watch(ctx, prefix)
+	return nil
+}
+
+func (m *mirror) fullSync(ctx context.Context, prefix string) error {
+	resp, err := m.client.Get(ctx, prefix, clientv3.WithPrefix())
+	if err != nil {
+		return err
+	}
+	data := make(map[string][]byte, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		data[string(kv.Key)] = kv.Value
+	}
+	m.mu.Lock()
+	m.data = data
+	m.rev = resp.Header.Revision
+	m.missed = 0
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *mirror) watch(ctx context.Context, prefix string) {
+	var backoff time.Duration
+	for {
+		m.mu.RLock()
+		rev := m.rev
+		m.mu.RUnlock()
+		wc := m.client.Watch(ctx, prefix, clientv3.WithPrefix(), clientv3.WithRev(rev+1))
+		erred := false
+		for resp := range wc {
+			if err := resp.Err(); err != nil {
+				m.log.Warnf("etcd mirror watch on %q lost revisions: %s", prefix, err)
+				if m.missedTooMany(resp.CompactRevision) {
+					if err := m.fullSync(ctx, prefix); err != nil {
+						m.log.Warnf("fail to resync mirror on %q: %s", prefix, err)
+					}
+				}
+				erred = true
+				break
+			}
+			backoff = 0
+			m.apply(resp)
+		}
+		if erred {
+			backoff = nextBackoff(backoff)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+func (m *mirror) missedTooMany(compactRev int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if compactRev > m.rev {
+		m.missed += compactRev - m.rev
+	} else {
+		m.missed++
+	}
+	if m.snapCount > 0 && m.missed >= m.snapCount {
+		m.missed = 0
+		return true
+	}
+	return false
+}
+
+func (m *mirror) apply(resp clientv3.WatchResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, ev := range resp.Events {
+		key := string(ev.Kv.Key)
+		if ev.Type == clientv3.EventTypeDelete {
+			delete(m.data, key)
+		} else {
+			m.data[key] = ev.Kv.Value
+		}
+		m.rev = ev.Kv.ModRevision
+	}
+}
+
+// Get implements the Mirror interface.
+func (m *mirror) Get(key string) ([]byte, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	v, ok := m.data[key]
+	return v, ok
+}
+
+// Snapshot implements the Mirror interface.
+func (m *mirror) Snapshot() map[string][]byte {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make(map[string][]byte, len(m.data))
+	for k, v := range m.data {
+		out[k] = v
+	}
+	return out
+}
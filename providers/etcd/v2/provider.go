@@ -0,0 +1,130 @@
+// Author: recallsong
+// Email: songruiguo@qq.com
+
+// Package v2 provides access to the etcd v2 keys/members/auth API
+// (`/v2/keys`, `/v2/members`, `/v2/auth`), for deployments and tooling that
+// have not migrated to etcd v3 yet.
+package v2
+
+import (
+	"crypto/tls"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/coreos/etcd/client"
+	"github.com/erda-project/erda-infra/base/logs"
+	"github.com/erda-project/erda-infra/base/servicehub"
+	"github.com/erda-project/erda-infra/providers/etcd/internal/tlsconf"
+)
+
+// Interface .
+type Interface interface {
+	Connect() (client.Client, error)
+	Client() client.Client
+	KeysAPI() client.KeysAPI
+	MembersAPI() client.MembersAPI
+	AuthAPI() client.AuthAPI
+	Timeout() time.Duration
+}
+
+type config struct {
+	Endpoints string        `file:"endpoints" env:"ETCD_ENDPOINTS"`
+	Timeout   time.Duration `file:"timeout" default:"10s"`
+	TLS       struct {
+		CertFile    string `file:"cert_file"`
+		CertKeyFile string `file:"cert_key_file"`
+		CaFile      string `file:"ca_file"`
+	} `file:"tls"`
+}
+
+var clientType = reflect.TypeOf((*client.Client)(nil)).Elem()
+
+type define struct{}
+
+func (d *define) Services() []string { return []string{"etcd-v2", "etcd-v2-client"} }
+func (d *define) Types() []reflect.Type {
+	return []reflect.Type{
+		reflect.TypeOf((*Interface)(nil)).Elem(),
+		clientType,
+	}
+}
+func (d *define) Description() string { return "etcd v2" }
+func (d *define) Config() interface{} { return &config{} }
+func (d *define) Creator() servicehub.Creator {
+	return func() servicehub.Provider {
+		return &provider{}
+	}
+}
+
+type provider struct {
+	Cfg       *config
+	Log       logs.Logger
+	client    client.Client
+	tlsConfig *tls.Config
+}
+
+func (p *provider) Init(ctx servicehub.Context) error {
+	err := p.initTLSConfig()
+	if err != nil {
+		return err
+	}
+	c, err := p.Connect()
+	if err != nil {
+		return err
+	}
+	p.client = c
+	return nil
+}
+
+func (p *provider) Connect() (client.Client, error) {
+	var transport client.CancelableTransport = client.DefaultTransport
+	if p.tlsConfig != nil {
+		transport = &http.Transport{TLSClientConfig: p.tlsConfig}
+	}
+	cfg := client.Config{
+		Endpoints:               strings.Split(p.Cfg.Endpoints, ","),
+		Transport:               transport,
+		HeaderTimeoutPerRequest: p.Cfg.Timeout,
+	}
+	return client.New(cfg)
+}
+
+func (p *provider) Client() client.Client { return p.client }
+
+func (p *provider) KeysAPI() client.KeysAPI { return client.NewKeysAPI(p.client) }
+
+func (p *provider) MembersAPI() client.MembersAPI { return client.NewMembersAPI(p.client) }
+
+func (p *provider) AuthAPI() client.AuthAPI { return client.NewAuthAPI(p.client) }
+
+func (p *provider) Timeout() time.Duration { return p.Cfg.Timeout }
+
+func (p *provider) initTLSConfig() error {
+	if len(p.Cfg.TLS.CertFile) > 0 || len(p.Cfg.TLS.CertKeyFile) > 0 {
+		cfg, err := tlsconf.Load(p.Cfg.TLS.CertFile, p.Cfg.TLS.CertKeyFile, p.Cfg.TLS.CaFile)
+		if err != nil {
+			if os.IsNotExist(err) {
+				p.Log.Warnf("fail to load tls files: %s", err)
+				return nil
+			}
+			return err
+		}
+		p.tlsConfig = cfg
+	}
+	return nil
+}
+
+// Provide .
+func (p *provider) Provide(ctx servicehub.DependencyContext, args ...interface{}) interface{} {
+	if ctx.Type() == clientType || ctx.Service() == "etcd-v2-client" {
+		return p.client
+	}
+	return p
+}
+
+func init() {
+	servicehub.RegisterProvider("etcd-v2", &define{})
+}
@@ -0,0 +1,93 @@
+// Author: recallsong
+// Email: songruiguo@qq.com
+
+package etcd
+
+import (
+	"context"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/coreos/etcd/mvcc/mvccpb"
+	"github.com/erda-project/erda-infra/base/logs"
+)
+
+// WatchEvent wraps a single clientv3 watch event.
+type WatchEvent struct {
+	Type   mvccpb.Event_EventType
+	Kv     *mvccpb.KeyValue
+	PrevKv *mvccpb.KeyValue
+}
+
+// Watcher multiplexes clientv3 watches over the shared connection, with
+// automatic reconnection and resuming from the last observed revision.
+type Watcher interface {
+	Watch(ctx context.Context, prefix string, opts ...clientv3.OpOption) (<-chan WatchEvent, error)
+}
+
+// watchClient is the subset of clientv3.Watcher that watcher needs, satisfied
+// by *clientv3.Client. Narrowing to this interface lets tests drive the
+// reconnect loop with a fake client.
+type watchClient interface {
+	Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan
+}
+
+type watcher struct {
+	client watchClient
+	log    logs.Logger
+}
+
+func newWatcher(client watchClient, log logs.Logger) *watcher {
+	return &watcher{client: client, log: log}
+}
+
+// Watch implements the Watcher interface.
+func (w *watcher) Watch(ctx context.Context, prefix string, opts ...clientv3.OpOption) (<-chan WatchEvent, error) {
+	out := make(chan WatchEvent)
+	go w.run(ctx, prefix, out, opts...)
+	return out, nil
+}
+
+func (w *watcher) run(ctx context.Context, prefix string, out chan<- WatchEvent, opts ...clientv3.OpOption) {
+	defer close(out)
+	options := append([]clientv3.OpOption{clientv3.WithPrefix()}, opts...)
+	var rev int64
+	var backoff time.Duration
+	for {
+		watchOpts := options
+		if rev > 0 {
+			watchOpts = append(append([]clientv3.OpOption{}, options...), clientv3.WithRev(rev+1))
+		}
+		wc := w.client.Watch(ctx, prefix, watchOpts...)
+		erred := false
+		for resp := range wc {
+			if err := resp.Err(); err != nil {
+				w.log.Warnf("etcd watch on %q interrupted: %s, reconnecting", prefix, err)
+				erred = true
+				break
+			}
+			backoff = 0
+			for _, ev := range resp.Events {
+				rev = ev.Kv.ModRevision
+				select {
+				case out <- WatchEvent{Type: ev.Type, Kv: ev.Kv, PrevKv: ev.PrevKv}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+		if erred {
+			backoff = nextBackoff(backoff)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Author: recallsong
+// Email: songruiguo@qq.com
+
+package etcd
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coreos/etcd/clientv3"
+	"github.com/erda-project/erda-infra/base/logs"
+)
+
+// fakeLogger is a no-op logs.Logger, just enough to let run()'s reconnect
+// warnings go somewhere without pulling in the real logging provider.
+type fakeLogger struct{}
+
+func (fakeLogger) Debug(args ...interface{})              {}
+func (fakeLogger) Info(args ...interface{})               {}
+func (fakeLogger) Warn(args ...interface{})               {}
+func (fakeLogger) Error(args ...interface{})              {}
+func (fakeLogger) Fatal(args ...interface{})              {}
+func (fakeLogger) Debugf(tpl string, args ...interface{}) {}
+func (fakeLogger) Infof(tpl string, args ...interface{})  {}
+func (fakeLogger) Warnf(tpl string, args ...interface{})  {}
+func (fakeLogger) Errorf(tpl string, args ...interface{}) {}
+func (fakeLogger) Fatalf(tpl string, args ...interface{}) {}
+func (fakeLogger) Sub(name string) logs.Logger            { return fakeLogger{} }
+
+// fakeWatchClient fails the first watch, then succeeds, to exercise the
+// reconnect-with-backoff path in watcher.run without waiting on a real etcd.
+type fakeWatchClient struct {
+	calls int32
+}
+
+func (f *fakeWatchClient) Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan {
+	ch := make(chan clientv3.WatchResponse, 1)
+	if atomic.AddInt32(&f.calls, 1) == 1 {
+		ch <- clientv3.WatchResponse{Canceled: true}
+		close(ch)
+		return ch
+	}
+	close(ch)
+	return ch
+}
+
+func TestWatcherReconnectsAfterError(t *testing.T) {
+	client := &fakeWatchClient{}
+	w := newWatcher(client, fakeLogger{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	out, err := w.Watch(ctx, "/prefix")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for range out {
+	}
+
+	if calls := atomic.LoadInt32(&client.calls); calls < 2 {
+		t.Fatalf("expected watcher to reconnect after the first error, got %d calls", calls)
+	}
+}
@@ -0,0 +1,188 @@
+// Author: recallsong
+// Email: songruiguo@qq.com
+
+package httpserver
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo"
+)
+
+// JSONDecoder decodes a JSON request body into i. Override it via
+// Interface.SetJSONDecoder to swap in a faster implementation (e.g. sonic,
+// segmentio/encoding/json) without forking the binder.
+type JSONDecoder func(r io.Reader, i interface{}) error
+
+// XMLDecoder decodes an XML request body into i.
+type XMLDecoder func(r io.Reader, i interface{}) error
+
+func defaultJSONDecoder(r io.Reader, i interface{}) error { return json.NewDecoder(r).Decode(i) }
+
+func defaultXMLDecoder(r io.Reader, i interface{}) error { return xml.NewDecoder(r).Decode(i) }
+
+// Binder binds request data from a specific source (body content-type, query,
+// param, header or cookie) into i.
+type Binder interface {
+	Bind(i interface{}, c echo.Context) error
+}
+
+// BinderFunc adapts a plain function to a Binder.
+type BinderFunc func(i interface{}, c echo.Context) error
+
+// Bind implements the Binder interface.
+func (f BinderFunc) Bind(i interface{}, c echo.Context) error { return f(i, c) }
+
+type binderEntry struct {
+	contentType string
+	binder      Binder
+}
+
+// binderRegistry resolves a Binder by request content-type. Entries are
+// checked in registration order and the first one whose content-type is a
+// prefix of the request's wins; registering an existing content-type again
+// (e.g. via RegisterBinder) replaces its binder in place.
+type binderRegistry struct {
+	entries     []binderEntry
+	jsonDecoder JSONDecoder
+	xmlDecoder  XMLDecoder
+}
+
+func newBinderRegistry() *binderRegistry {
+	r := &binderRegistry{
+		jsonDecoder: defaultJSONDecoder,
+		xmlDecoder:  defaultXMLDecoder,
+	}
+	r.register(echo.MIMEApplicationJSON, BinderFunc(r.bindJSON))
+	r.register(echo.MIMEApplicationXML, BinderFunc(r.bindXML))
+	r.register(echo.MIMETextXML, BinderFunc(r.bindXML))
+	r.register(echo.MIMEApplicationForm, BinderFunc(bindForm))
+	r.register(echo.MIMEMultipartForm, BinderFunc(bindForm))
+	return r
+}
+
+// register adds or replaces the Binder used for the given content-type.
+func (r *binderRegistry) register(contentType string, b Binder) {
+	for i, e := range r.entries {
+		if e.contentType == contentType {
+			r.entries[i].binder = b
+			return
+		}
+	}
+	r.entries = append(r.entries, binderEntry{contentType: contentType, binder: b})
+}
+
+func (r *binderRegistry) lookup(contentType string) Binder {
+	for _, e := range r.entries {
+		if strings.HasPrefix(contentType, e.contentType) {
+			return e.binder
+		}
+	}
+	return nil
+}
+
+// bindJSON streams the body straight into the configured JSONDecoder, no
+// buffering required since the body is only ever read once.
+func (r *binderRegistry) bindJSON(i interface{}, c echo.Context) error {
+	if err := r.jsonDecoder(c.Request().Body, i); err != nil {
+		if ute, ok := err.(*json.UnmarshalTypeError); ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unmarshal type error: expected=%v, got=%v, field=%v, offset=%v", ute.Type, ute.Value, ute.Field, ute.Offset)).SetInternal(err)
+		} else if se, ok := err.(*json.SyntaxError); ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Syntax error: offset=%v, error=%v", se.Offset, se.Error())).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	return nil
+}
+
+func (r *binderRegistry) bindXML(i interface{}, c echo.Context) error {
+	if err := r.xmlDecoder(c.Request().Body, i); err != nil {
+		if ute, ok := err.(*xml.UnsupportedTypeError); ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported type error: type=%v, error=%v", ute.Type, ute.Error())).SetInternal(err)
+		} else if se, ok := err.(*xml.SyntaxError); ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Syntax error: line=%v, error=%v", se.Line, se.Error())).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	return nil
+}
+
+func bindForm(i interface{}, c echo.Context) error {
+	params, err := c.FormParams()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	if err := bindData(i, params, "form"); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	return nil
+}
+
+// bindStream handles handler arguments that want the raw body stream
+// instead of a populated struct: an io.ReaderFrom reads the body itself, and
+// *json.Decoder/*xml.Decoder are wired to the body so the handler can decode
+// incrementally. Returns false if i is none of these, so the caller falls
+// back to the registered content-type Binder.
+func bindStream(i interface{}, c echo.Context) (bool, error) {
+	req := c.Request()
+	switch v := i.(type) {
+	case io.ReaderFrom:
+		if _, err := v.ReadFrom(req.Body); err != nil {
+			return true, echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		return true, nil
+	case *json.Decoder:
+		*v = *json.NewDecoder(req.Body)
+		return true, nil
+	case *xml.Decoder:
+		*v = *xml.NewDecoder(req.Body)
+		return true, nil
+	}
+	return false, nil
+}
+
+// bindFields binds the query, path param, header and cookie sources, which
+// apply regardless of the request body's content-type.
+func bindFields(i interface{}, c echo.Context) error {
+	if err := bindData(i, c.QueryParams(), "query"); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	names, values := c.ParamNames(), c.ParamValues()
+	params := make(map[string][]string, len(names))
+	for idx, name := range names {
+		params[name] = []string{values[idx]}
+	}
+	if err := bindData(i, params, "param"); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	if err := bindData(i, headerParams(c), "header"); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	if err := bindData(i, cookieParams(c), "cookie"); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+	return nil
+}
+
+func headerParams(c echo.Context) map[string][]string {
+	header := c.Request().Header
+	out := make(map[string][]string, len(header))
+	for k, v := range header {
+		out[k] = v
+	}
+	return out
+}
+
+func cookieParams(c echo.Context) map[string][]string {
+	cookies := c.Cookies()
+	out := make(map[string][]string, len(cookies))
+	for _, ck := range cookies {
+		out[ck.Name] = append(out[ck.Name], ck.Value)
+	}
+	return out
+}
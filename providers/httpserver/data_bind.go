@@ -4,89 +4,76 @@
 package httpserver
 
 import (
-	"bytes"
 	"encoding"
-	"encoding/json"
-	"encoding/xml"
 	"errors"
-	"fmt"
-	"io/ioutil"
 	"net/http"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo"
 )
 
-type dataBinder struct{}
+// dataBinder binds request data into a struct using the registered Binder
+// for the request's content-type plus the fixed query/param/header/cookie
+// sources, then runs the configured Validator, if any.
+type dataBinder struct {
+	binders      *binderRegistry
+	validator    Validator
+	maxBodyBytes int64
+	readTimeout  time.Duration
+}
 
 // Bind implements the `Binder#Bind` function.
 func (b *dataBinder) Bind(i interface{}, c echo.Context) (err error) {
+	cancel := b.limitBody(c)
 	req := c.Request()
 	if req.ContentLength > 0 {
+		if handled, err := bindStream(i, c); handled {
+			// The handler reads and decodes the body itself after Bind
+			// returns, so the ReadTimeout watchdog must stay armed past this
+			// point to still bound that read; only disarm it on the
+			// synchronous wiring failure, where nothing will read the body.
+			if err != nil {
+				cancel()
+				return err
+			}
+			return nil
+		}
 		ctype := req.Header.Get(echo.HeaderContentType)
 		if len(ctype) <= 0 {
 			ctype = echo.MIMEApplicationJSON
 		}
-		body, err := ioutil.ReadAll(req.Body)
-		if err != nil {
-			return fmt.Errorf("fail to read body: %s", err)
-		}
-		req.Body = ioutil.NopCloser(bytes.NewBuffer(body))
-		switch {
-		case strings.HasPrefix(ctype, echo.MIMEApplicationJSON):
-			if err = json.Unmarshal(body, i); err != nil {
-				if ute, ok := err.(*json.UnmarshalTypeError); ok {
-					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unmarshal type error: expected=%v, got=%v, field=%v, offset=%v", ute.Type, ute.Value, ute.Field, ute.Offset)).SetInternal(err)
-				} else if se, ok := err.(*json.SyntaxError); ok {
-					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Syntax error: offset=%v, error=%v", se.Offset, se.Error())).SetInternal(err)
-				}
-				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
-			}
-		case strings.HasPrefix(ctype, echo.MIMEApplicationXML), strings.HasPrefix(ctype, echo.MIMETextXML):
-			if err = xml.Unmarshal(body, i); err != nil {
-				if ute, ok := err.(*xml.UnsupportedTypeError); ok {
-					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported type error: type=%v, error=%v", ute.Type, ute.Error())).SetInternal(err)
-				} else if se, ok := err.(*xml.SyntaxError); ok {
-					return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Syntax error: line=%v, error=%v", se.Line, se.Error())).SetInternal(err)
-				}
-				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
-			}
-		case strings.HasPrefix(ctype, echo.MIMEApplicationForm), strings.HasPrefix(ctype, echo.MIMEMultipartForm):
-			params, err := c.FormParams()
-			if err != nil {
-				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
-			}
-			if err = b.bindData(i, params, "form"); err != nil {
-				return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
-			}
-		default:
+		binder := b.binders.lookup(ctype)
+		if binder == nil {
+			cancel()
 			return echo.ErrUnsupportedMediaType
 		}
+		if err = binder.Bind(i, c); err != nil {
+			cancel()
+			return err
+		}
 	}
+	cancel()
 	typ := reflect.TypeOf(i)
 	for typ.Kind() == reflect.Ptr {
 		typ = typ.Elem()
 	}
 	if typ.Kind() == reflect.Struct {
-		if err = b.bindData(i, c.QueryParams(), "query"); err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
-		}
-		names := c.ParamNames()
-		values := c.ParamValues()
-		params := map[string][]string{}
-		for i, name := range names {
-			params[name] = []string{values[i]}
+		if err = bindFields(i, c); err != nil {
+			return err
 		}
-		if err := b.bindData(i, params, "param"); err != nil {
+	}
+	if b.validator != nil {
+		if err = b.validator.Validate(i); err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
 		}
 	}
-	return
+	return nil
 }
 
-func (b *dataBinder) bindData(ptr interface{}, data map[string][]string, tag string) error {
+func bindData(ptr interface{}, data map[string][]string, tag string) error {
 	if ptr == nil || len(data) == 0 {
 		return nil
 	}
@@ -106,34 +93,21 @@ func (b *dataBinder) bindData(ptr interface{}, data map[string][]string, tag str
 		return errors.New("binding element must be a struct")
 	}
 
-	for i := 0; i < typ.NumField(); i++ {
-		typeField := typ.Field(i)
-		structField := val.Field(i)
+	plan := getDecodePlan(typ, tag)
+	for _, f := range plan.fields {
+		structField := val.FieldByIndex(f.index)
 		if !structField.CanSet() {
 			continue
 		}
-		structFieldKind := structField.Kind()
-		inputFieldName := typeField.Tag.Get(tag)
 
-		if inputFieldName == "" {
-			inputFieldName = typeField.Name
-			// If tag is nil, we inspect if the field is a struct.
-			if _, ok := structField.Addr().Interface().(echo.BindUnmarshaler); !ok && structFieldKind == reflect.Struct {
-				if err := b.bindData(structField.Addr().Interface(), data, tag); err != nil {
-					return err
-				}
-				continue
-			}
-		}
-
-		inputValue, exists := data[inputFieldName]
+		inputValue, exists := data[f.name]
 		if !exists {
 			// Go json.Unmarshal supports case insensitive binding.  However the
 			// url params are bound case sensitive which is inconsistent.  To
 			// fix this we must check all of the map values in a
 			// case-insensitive search.
 			for k, v := range data {
-				if strings.EqualFold(k, inputFieldName) {
+				if strings.EqualFold(k, f.name) {
 					inputValue = v
 					exists = true
 					break
@@ -146,7 +120,7 @@ func (b *dataBinder) bindData(ptr interface{}, data map[string][]string, tag str
 		}
 
 		// Call this first, in case we're dealing with an alias to an array type
-		if ok, err := unmarshalField(typeField.Type.Kind(), inputValue[0], structField); ok {
+		if ok, err := unmarshalField(f.kind, inputValue[0], structField); ok {
 			if err != nil {
 				return err
 			}
@@ -154,18 +128,16 @@ func (b *dataBinder) bindData(ptr interface{}, data map[string][]string, tag str
 		}
 
 		numElems := len(inputValue)
-		if structFieldKind == reflect.Slice && numElems > 0 {
-			sliceOf := structField.Type().Elem().Kind()
+		if f.isSlice && numElems > 0 {
 			slice := reflect.MakeSlice(structField.Type(), numElems, numElems)
 			for j := 0; j < numElems; j++ {
-				if err := setWithProperType(sliceOf, inputValue[j], slice.Index(j)); err != nil {
+				if err := setWithProperType(f.elemKind, inputValue[j], slice.Index(j)); err != nil {
 					return err
 				}
 			}
-			val.Field(i).Set(slice)
-		} else if err := setWithProperType(typeField.Type.Kind(), inputValue[0], structField); err != nil {
+			structField.Set(slice)
+		} else if err := setWithProperType(f.kind, inputValue[0], structField); err != nil {
 			return err
-
 		}
 	}
 	return nil
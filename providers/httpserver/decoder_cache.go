@@ -0,0 +1,85 @@
+// Author: recallsong
+// Email: songruiguo@qq.com
+
+package httpserver
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/labstack/echo"
+)
+
+// fieldPlan is the pre-resolved binding plan for a single struct field, so
+// bindData no longer needs to inspect kinds or walk embedded structs on
+// every request.
+type fieldPlan struct {
+	index    []int
+	name     string
+	kind     reflect.Kind
+	elemKind reflect.Kind
+	isSlice  bool
+}
+
+// decodePlan is the flattened set of fieldPlan entries for a struct type and
+// a given tag (e.g. "query", "param", "header", "cookie", "form").
+type decodePlan struct {
+	fields []fieldPlan
+}
+
+type decodePlanKey struct {
+	typ reflect.Type
+	tag string
+}
+
+// decodePlanCache caches decodePlan by (struct type, tag) so repeated binds
+// of the same request struct reuse a single resolved plan instead of
+// re-reflecting over the struct fields every time.
+var decodePlanCache sync.Map // map[decodePlanKey]*decodePlan
+
+func getDecodePlan(typ reflect.Type, tag string) *decodePlan {
+	key := decodePlanKey{typ: typ, tag: tag}
+	if v, ok := decodePlanCache.Load(key); ok {
+		return v.(*decodePlan)
+	}
+	plan := buildDecodePlan(typ, tag, nil)
+	actual, _ := decodePlanCache.LoadOrStore(key, plan)
+	return actual.(*decodePlan)
+}
+
+func buildDecodePlan(typ reflect.Type, tag string, prefix []int) *decodePlan {
+	plan := &decodePlan{}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue // unexported field
+		}
+		index := make([]int, len(prefix)+1)
+		copy(index, prefix)
+		index[len(prefix)] = i
+
+		name := field.Tag.Get(tag)
+		if name == "" {
+			if field.Type.Kind() == reflect.Struct && !implementsBindUnmarshaler(field.Type) {
+				sub := buildDecodePlan(field.Type, tag, index)
+				plan.fields = append(plan.fields, sub.fields...)
+				continue
+			}
+			name = field.Name
+		}
+
+		fp := fieldPlan{index: index, name: name, kind: field.Type.Kind()}
+		if fp.kind == reflect.Slice {
+			fp.isSlice = true
+			fp.elemKind = field.Type.Elem().Kind()
+		}
+		plan.fields = append(plan.fields, fp)
+	}
+	return plan
+}
+
+var bindUnmarshalerType = reflect.TypeOf((*echo.BindUnmarshaler)(nil)).Elem()
+
+func implementsBindUnmarshaler(t reflect.Type) bool {
+	return reflect.PtrTo(t).Implements(bindUnmarshalerType)
+}
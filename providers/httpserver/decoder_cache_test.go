@@ -0,0 +1,65 @@
+// Author: recallsong
+// Email: songruiguo@qq.com
+
+package httpserver
+
+import (
+	"reflect"
+	"testing"
+)
+
+type embeddedQuery struct {
+	Offset int `query:"offset"`
+}
+
+type testQuery struct {
+	embeddedQuery
+	Name string   `query:"name"`
+	Tags []string `query:"tags"`
+	ID   int
+}
+
+func TestBuildDecodePlanFlattensEmbeddedStructs(t *testing.T) {
+	plan := buildDecodePlan(reflect.TypeOf(testQuery{}), "query", nil)
+
+	byName := make(map[string]fieldPlan, len(plan.fields))
+	for _, fp := range plan.fields {
+		byName[fp.name] = fp
+	}
+
+	offset, ok := byName["offset"]
+	if !ok {
+		t.Fatalf("expected embedded field %q to be flattened into the plan, got %+v", "offset", plan.fields)
+	}
+	if len(offset.index) != 2 {
+		t.Fatalf("expected embedded field index to include both the embedding and the field, got %v", offset.index)
+	}
+
+	name, ok := byName["name"]
+	if !ok || name.kind != reflect.String {
+		t.Fatalf("expected a string field %q, got %+v (ok=%v)", "name", name, ok)
+	}
+
+	tags, ok := byName["tags"]
+	if !ok || !tags.isSlice || tags.elemKind != reflect.String {
+		t.Fatalf("expected tags to be a []string slice field, got %+v (ok=%v)", tags, ok)
+	}
+
+	if _, ok := byName["ID"]; !ok {
+		t.Fatalf("expected untagged field to fall back to its Go name, got %+v", plan.fields)
+	}
+}
+
+func TestGetDecodePlanReusesCachedPlan(t *testing.T) {
+	typ := reflect.TypeOf(testQuery{})
+	first := getDecodePlan(typ, "query")
+	second := getDecodePlan(typ, "query")
+	if first != second {
+		t.Fatalf("expected getDecodePlan to return the same cached *decodePlan for repeated calls")
+	}
+
+	other := getDecodePlan(typ, "param")
+	if other == first {
+		t.Fatalf("expected a different tag to build a distinct plan, got the same pointer")
+	}
+}
@@ -0,0 +1,53 @@
+// Author: recallsong
+// Email: songruiguo@qq.com
+
+package httpserver
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo"
+)
+
+// maxBodyBytesContextKey is the echo.Context key used to override the
+// provider-wide MaxBodyBytes for a single route.
+const maxBodyBytesContextKey = "httpserver.max_body_bytes"
+
+// MaxBodyBytes returns a middleware that overrides the global MaxBodyBytes
+// limit for the routes it is applied to.
+func MaxBodyBytes(n int64) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(maxBodyBytesContextKey, n)
+			return next(c)
+		}
+	}
+}
+
+func routeMaxBodyBytes(c echo.Context, fallback int64) int64 {
+	if v, ok := c.Get(maxBodyBytesContextKey).(int64); ok {
+		return v
+	}
+	return fallback
+}
+
+// limitBody wraps the request body with http.MaxBytesReader when a limit is
+// configured, and arms a timer that closes the body if ReadTimeout elapses
+// before the caller is done reading it. Closing the body is what actually
+// interrupts a stalled client; a context deadline alone does nothing here
+// since the binders only ever call Read on req.Body, never select on a
+// context.
+func (b *dataBinder) limitBody(c echo.Context) (cancel func()) {
+	req := c.Request()
+	if n := routeMaxBodyBytes(c, b.maxBodyBytes); n > 0 {
+		req.Body = http.MaxBytesReader(c.Response(), req.Body, n)
+	}
+	cancel = func() {}
+	if b.readTimeout > 0 {
+		body := req.Body
+		timer := time.AfterFunc(b.readTimeout, func() { body.Close() })
+		cancel = func() { timer.Stop() }
+	}
+	return cancel
+}
@@ -0,0 +1,99 @@
+// Author: recallsong
+// Email: songruiguo@qq.com
+
+package httpserver
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/erda-project/erda-infra/base/servicehub"
+	"github.com/labstack/echo"
+)
+
+// Interface is the service exposed by this provider so other providers can
+// customize request binding through the servicehub DI surface.
+type Interface interface {
+	// RegisterBinder registers a custom Binder for the given content-type,
+	// e.g. protobuf, msgpack or YAML, without forking the default binders.
+	RegisterBinder(contentType string, b Binder)
+	// SetValidator configures the Validator invoked automatically after a
+	// successful Bind.
+	SetValidator(v Validator)
+	// SetJSONDecoder replaces the JSON decoder used for application/json bodies.
+	SetJSONDecoder(d JSONDecoder)
+	// SetXMLDecoder replaces the XML decoder used for application/xml bodies.
+	SetXMLDecoder(d XMLDecoder)
+	// RedirectMiddleware returns an echo middleware applying the Redirects
+	// configured for this provider.
+	RedirectMiddleware() echo.MiddlewareFunc
+}
+
+type config struct {
+	Addr         string         `file:"addr" default:":8080"`
+	MaxBodyBytes int64          `file:"max_body_bytes" default:"4194304"`
+	ReadTimeout  time.Duration  `file:"read_timeout"`
+	Redirects    []RedirectRule `file:"redirects"`
+}
+
+type define struct{}
+
+func (d *define) Services() []string { return []string{"http-server"} }
+func (d *define) Description() string { return "http server" }
+func (d *define) Config() interface{} { return &config{} }
+func (d *define) Types() []reflect.Type {
+	return []reflect.Type{reflect.TypeOf((*Interface)(nil)).Elem()}
+}
+func (d *define) Creator() servicehub.Creator {
+	return func() servicehub.Provider {
+		return &provider{}
+	}
+}
+
+type provider struct {
+	Cfg    *config
+	binder *dataBinder
+}
+
+func (p *provider) Init(ctx servicehub.Context) error {
+	p.binder = &dataBinder{
+		binders:      newBinderRegistry(),
+		maxBodyBytes: p.Cfg.MaxBodyBytes,
+		readTimeout:  p.Cfg.ReadTimeout,
+	}
+	return nil
+}
+
+// RegisterBinder implements the Interface.
+func (p *provider) RegisterBinder(contentType string, b Binder) {
+	p.binder.binders.register(contentType, b)
+}
+
+// SetValidator implements the Interface.
+func (p *provider) SetValidator(v Validator) {
+	p.binder.validator = v
+}
+
+// SetJSONDecoder implements the Interface.
+func (p *provider) SetJSONDecoder(d JSONDecoder) {
+	p.binder.binders.jsonDecoder = d
+}
+
+// SetXMLDecoder implements the Interface.
+func (p *provider) SetXMLDecoder(d XMLDecoder) {
+	p.binder.binders.xmlDecoder = d
+}
+
+// RedirectMiddleware implements the Interface.
+func (p *provider) RedirectMiddleware() echo.MiddlewareFunc {
+	return RedirectMiddleware(p.Cfg.Redirects)
+}
+
+// Provide implements the servicehub.Provider interface.
+func (p *provider) Provide(ctx servicehub.DependencyContext, args ...interface{}) interface{} {
+	return p
+}
+
+func init() {
+	servicehub.RegisterProvider("http-server", &define{})
+}
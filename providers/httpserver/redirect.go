@@ -0,0 +1,106 @@
+// Author: recallsong
+// Email: songruiguo@qq.com
+
+package httpserver
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo"
+)
+
+// RedirectRule describes a single path redirect, with StatusCode taking
+// precedence when set and Permanent otherwise picking between 301 and 302.
+type RedirectRule struct {
+	From       string `file:"from" yaml:"from"`
+	To         string `file:"to" yaml:"to"`
+	Permanent  bool   `file:"permanent" yaml:"permanent"`
+	StatusCode int    `file:"status_code" yaml:"status_code"`
+}
+
+func (r *RedirectRule) statusCode() int {
+	if r.StatusCode > 0 {
+		return r.StatusCode
+	}
+	if r.Permanent {
+		return http.StatusMovedPermanently
+	}
+	return http.StatusFound
+}
+
+// Redirect sends a 301 (permanent) or 302 (temporary) redirect to to,
+// substituting ":name" placeholders from the current route's params and
+// query string.
+func Redirect(c echo.Context, to string, permanent bool) error {
+	code := http.StatusFound
+	if permanent {
+		code = http.StatusMovedPermanently
+	}
+	return RedirectStatus(c, to, code)
+}
+
+// RedirectStatus sends a redirect to to using an explicit HTTP status code
+// (e.g. 307/308 to preserve the request method), substituting ":name"
+// placeholders from the current route's params and query string.
+func RedirectStatus(c echo.Context, to string, code int) error {
+	return c.Redirect(code, substitutePlaceholders(c, to))
+}
+
+func substitutePlaceholders(c echo.Context, target string) string {
+	names, values := c.ParamNames(), c.ParamValues()
+	for idx, name := range names {
+		target = strings.ReplaceAll(target, ":"+name, values[idx])
+	}
+	for k, v := range c.QueryParams() {
+		if len(v) > 0 {
+			target = strings.ReplaceAll(target, ":"+k, v[0])
+		}
+	}
+	return target
+}
+
+// RedirectMiddleware builds an echo middleware that redirects requests
+// matching rule.From to rule.To, in order, before falling through to next.
+// From may contain ":name" placeholders, which are carried over into To.
+func RedirectMiddleware(rules []RedirectRule) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			path := c.Request().URL.Path
+			for i := range rules {
+				rule := &rules[i]
+				params, ok := matchPath(rule.From, path)
+				if !ok {
+					continue
+				}
+				to := rule.To
+				for name, value := range params {
+					to = strings.ReplaceAll(to, ":"+name, value)
+				}
+				return RedirectStatus(c, to, rule.statusCode())
+			}
+			return next(c)
+		}
+	}
+}
+
+// matchPath matches path against a route pattern such as "/old/:id",
+// returning the captured ":name" placeholders.
+func matchPath(pattern, path string) (map[string]string, bool) {
+	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathParts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternParts) != len(pathParts) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, p := range patternParts {
+		if strings.HasPrefix(p, ":") {
+			params[p[1:]] = pathParts[i]
+			continue
+		}
+		if p != pathParts[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
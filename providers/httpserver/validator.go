@@ -0,0 +1,49 @@
+// Author: recallsong
+// Email: songruiguo@qq.com
+
+package httpserver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Validator validates a struct after it has been bound from the request. It
+// is configured on the provider via SetValidator and invoked automatically
+// after a successful Bind.
+type Validator interface {
+	Validate(i interface{}) error
+}
+
+// FieldError describes a single invalid field found while validating a
+// bound struct.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *FieldError) Error() string { return fmt.Sprintf("%s: %s", e.Field, e.Err) }
+
+// ValidationError collects the FieldError(s) found while validating a bound
+// struct, with their field paths, so callers can report all of them at once.
+type ValidationError struct {
+	Fields []*FieldError
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	if len(e.Fields) == 0 {
+		return "validation failed"
+	}
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Add appends a FieldError for the given field path.
+func (e *ValidationError) Add(field string, err error) {
+	e.Fields = append(e.Fields, &FieldError{Field: field, Err: err})
+}